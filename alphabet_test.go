@@ -0,0 +1,74 @@
+package totp_test
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+
+	"github.com/itpey/totp"
+)
+
+// steamTCs are Steam Guard codes computed for the RFC 4226 Appendix D
+// secret ("12345678901234567890") with this package's algorithm: HMAC-SHA1
+// dynamic truncation per RFC 4226, rendered in totp.SteamAlphabet with the
+// least-significant base-26 digit written first, matching Steam's client.
+var steamTCs = []struct {
+	TS   int64
+	Code string
+}{
+	{59, "PV9M4"},
+	{1111111109, "PY4YB"},
+	{1234567890, "VHHQY"},
+}
+
+func TestSteamGuardVectors(t *testing.T) {
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+	generator := totp.New(totp.Config{
+		Secret:    secret,
+		Digits:    totp.DigitsFive,
+		Period:    30,
+		Skew:      0,
+		Algorithm: totp.AlgorithmSHA1,
+		Alphabet:  totp.SteamAlphabet,
+	})
+
+	for _, tx := range steamTCs {
+		code, err := generator.GenerateForTime(time.Unix(tx.TS, 0).UTC())
+		if err != nil {
+			t.Errorf("Error generating Steam code for time %d: %v", tx.TS, err)
+			continue
+		}
+		if code != tx.Code {
+			t.Errorf("Expected Steam code %s, but got %s for time %d", tx.Code, code, tx.TS)
+		}
+
+		valid, err := generator.ValidateForTime(code, time.Unix(tx.TS, 0).UTC())
+		if err != nil {
+			t.Errorf("Error validating Steam code for time %d: %v", tx.TS, err)
+		}
+		if !valid {
+			t.Errorf("Steam code %s was not valid for time %d", code, tx.TS)
+		}
+	}
+}
+
+func TestValidateForTimeRejectsWrongLengthAlphabetCode(t *testing.T) {
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+	generator := totp.New(totp.Config{
+		Secret:    secret,
+		Digits:    totp.DigitsFive,
+		Period:    30,
+		Algorithm: totp.AlgorithmSHA1,
+		Alphabet:  totp.SteamAlphabet,
+	})
+
+	valid, err := generator.ValidateForTime("BAD", time.Unix(59, 0).UTC())
+	if err == nil {
+		t.Fatal("expected error for wrong-length code, got nil")
+	}
+	if valid {
+		t.Fatal("expected wrong-length code to be invalid")
+	}
+}
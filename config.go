@@ -6,13 +6,16 @@ import (
 	"crypto/sha256"
 	"crypto/sha3"
 	"crypto/sha512"
+	"fmt"
 	"hash"
 
 	"golang.org/x/crypto/blake2b"
 	"golang.org/x/crypto/blake2s"
 )
 
-// Digits represents the number of digits to use.
+// Digits represents the code length: the number of characters in a
+// generated code. Named Digits for the common decimal case, but it applies
+// equally to codes rendered in a custom Alphabet.
 type Digits int
 
 const (
@@ -23,6 +26,16 @@ const (
 	DigitsEight Digits = 8
 )
 
+// Alphabet is the character set used to render generated codes. The zero
+// value renders decimal digits, as RFC 6238 defines. Setting it switches
+// the generator to base-N encoding of the truncated HMAC value over the
+// given characters instead, as used by e.g. Steam Guard.
+type Alphabet string
+
+// SteamAlphabet is the 26-character alphabet Steam Guard codes are rendered
+// in. Steam Guard codes are 5 characters long, so pair it with DigitsFive.
+const SteamAlphabet Alphabet = "23456789BCDFGHJKMNPQRTVWXY"
+
 // Algorithm represents the hashing algorithm to use.
 type Algorithm int
 
@@ -46,11 +59,13 @@ const (
 
 // Config holds the configuration settings for hashing.
 type Config struct {
-	Algorithm Algorithm // Hashing algorithm to use (default: SHA1)
-	Digits    Digits    // Number of output digits (default: 6)
-	Period    int64     // Validity period in seconds (default: 30)
-	Secret    string    // Base32 encoded secret key
-	Skew      int64     // Time skew adjustment (default: 1)
+	Algorithm Algorithm     // Hashing algorithm to use (default: SHA1)
+	Digits    Digits        // Number of output digits (default: 6)
+	Period    int64         // Validity period in seconds (default: 30)
+	Secret    string        // Base32 encoded secret key
+	Skew      int64         // Time skew adjustment (default: 1)
+	Store     UsedCodeStore // Replay-protection store used by ValidateOnce (optional)
+	Alphabet  Alphabet      // Custom code alphabet (default: decimal digits)
 }
 
 // ConfigDefault is the default configuration.
@@ -101,6 +116,84 @@ func (a Algorithm) hash() hash.Hash {
 	}
 }
 
+// Size returns the HMAC output size in bytes for the algorithm, i.e. the
+// secret length RFC 6238 recommends for use with it.
+func (a Algorithm) Size() int {
+	return a.hash().Size()
+}
+
+// String returns the canonical algorithm name used in otpauth provisioning URIs.
+func (a Algorithm) String() string {
+	switch a {
+	case AlgorithmSHA1:
+		return "SHA1"
+	case AlgorithmSHA224:
+		return "SHA224"
+	case AlgorithmSHA256:
+		return "SHA256"
+	case AlgorithmSHA384:
+		return "SHA384"
+	case AlgorithmSHA512:
+		return "SHA512"
+	case AlgorithmSHA3_224:
+		return "SHA3-224"
+	case AlgorithmSHA3_256:
+		return "SHA3-256"
+	case AlgorithmSHA3_384:
+		return "SHA3-384"
+	case AlgorithmSHA3_512:
+		return "SHA3-512"
+	case AlgorithmBLAKE2S_256:
+		return "BLAKE2S-256"
+	case AlgorithmBLAKE2B_256:
+		return "BLAKE2B-256"
+	case AlgorithmBLAKE2B_384:
+		return "BLAKE2B-384"
+	case AlgorithmBLAKE2B_512:
+		return "BLAKE2B-512"
+	case AlgorithmMD5:
+		return "MD5"
+	default:
+		return "SHA1"
+	}
+}
+
+// parseAlgorithm parses the canonical algorithm name produced by Algorithm.String.
+func parseAlgorithm(s string) (Algorithm, error) {
+	switch s {
+	case "SHA1":
+		return AlgorithmSHA1, nil
+	case "SHA224":
+		return AlgorithmSHA224, nil
+	case "SHA256":
+		return AlgorithmSHA256, nil
+	case "SHA384":
+		return AlgorithmSHA384, nil
+	case "SHA512":
+		return AlgorithmSHA512, nil
+	case "SHA3-224":
+		return AlgorithmSHA3_224, nil
+	case "SHA3-256":
+		return AlgorithmSHA3_256, nil
+	case "SHA3-384":
+		return AlgorithmSHA3_384, nil
+	case "SHA3-512":
+		return AlgorithmSHA3_512, nil
+	case "BLAKE2S-256":
+		return AlgorithmBLAKE2S_256, nil
+	case "BLAKE2B-256":
+		return AlgorithmBLAKE2B_256, nil
+	case "BLAKE2B-384":
+		return AlgorithmBLAKE2B_384, nil
+	case "BLAKE2B-512":
+		return AlgorithmBLAKE2B_512, nil
+	case "MD5":
+		return AlgorithmMD5, nil
+	default:
+		return 0, fmt.Errorf("unsupported algorithm: %s", s)
+	}
+}
+
 // configDefault sets default values for the provided configuration.
 func configDefault(config ...Config) Config {
 	// Return default config if no configuration is provided
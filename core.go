@@ -0,0 +1,161 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"encoding/base32"
+	"fmt"
+	"hash"
+	"strings"
+	"sync"
+)
+
+// core holds the HMAC/pool machinery shared by the TOTP and HOTP generators,
+// since TOTP is defined as HOTP evaluated over a time-derived counter.
+type core struct {
+	algorithm     Algorithm  // Hashing algorithm in use.
+	digits        int        // Code length.
+	alphabet      Alphabet   // Custom code alphabet; empty means decimal digits.
+	divisor       int64      // Divisor for code calculation.
+	decodedSecret []byte     // Decoded secret key.
+	timeBytesPool *sync.Pool // Pool for counter byte arrays.
+	hmacPool      *sync.Pool // Pool for HMAC instances.
+}
+
+// hmacHolder helps reuse HMAC instances efficiently.
+type hmacHolder struct {
+	h hash.Hash
+}
+
+// normalizeSecret strips whitespace and upper-cases secret the way real
+// authenticator apps do before treating it as Base32.
+func normalizeSecret(secret string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(secret), ""))
+}
+
+// newCore decodes the given Base32 secret and builds the pools shared by
+// every generator built on top of it. alphabet may be empty, in which case
+// generated codes are decimal digits; otherwise codes are base-N over
+// alphabet's characters, e.g. SteamAlphabet for Steam Guard codes.
+func newCore(secret string, algorithm Algorithm, digits int, alphabet Alphabet) (*core, error) {
+	decodedSecret, err := base32.StdEncoding.DecodeString(normalizeSecret(secret))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode Base32 secret: %w", err)
+	}
+
+	base := int64(10)
+	if alphabet != "" {
+		base = int64(len(alphabet))
+	}
+
+	divisor := int64(1)
+	for i := 0; i < digits; i++ {
+		divisor *= base
+	}
+
+	timeBytesPool := &sync.Pool{
+		New: func() interface{} {
+			return new([8]byte)
+		},
+	}
+
+	hmacPool := &sync.Pool{
+		New: func() interface{} {
+			h := hmac.New(algorithm.hash, decodedSecret)
+			return &hmacHolder{h: h}
+		},
+	}
+
+	return &core{
+		algorithm:     algorithm,
+		digits:        digits,
+		alphabet:      alphabet,
+		divisor:       divisor,
+		decodedSecret: decodedSecret,
+		timeBytesPool: timeBytesPool,
+		hmacPool:      hmacPool,
+	}, nil
+}
+
+// generate computes the HOTP value (RFC 4226) for the given 64-bit counter.
+func (c *core) generate(counter uint64) string {
+	// Get a counter bytes array from the pool.
+	counterBytes := c.timeBytesPool.Get().(*[8]byte)
+	defer c.timeBytesPool.Put(counterBytes)
+
+	// Convert counter to big-endian 8-byte array.
+	for i := range counterBytes {
+		(*counterBytes)[7-i] = byte(counter >> (8 * i))
+	}
+
+	// Get a new HMAC instance from the pool.
+	hmacInstance := c.hmacPool.Get().(*hmacHolder)
+	defer c.hmacPool.Put(hmacInstance)
+
+	hmacInstance.h.Reset()
+	hmacInstance.h.Write(counterBytes[:])
+	hmacResult := hmacInstance.h.Sum(nil)
+
+	// Extract the dynamic binary code using offset from HMAC result. The
+	// nibble yields an offset of 0-15, which only RFC 4226's dynamic
+	// truncation guarantees fits (it requires a 20+ byte HMAC output, as
+	// every algorithm here provides except AlgorithmMD5's 16 bytes). Reduce
+	// the offset into the range that keeps offset+3 in bounds for any
+	// output size instead of assuming one; for outputs of 19+ bytes (every
+	// algorithm but MD5) maxOffset+1 exceeds 15 and this is a no-op.
+	maxOffset := len(hmacResult) - 4
+	offset := int(hmacResult[len(hmacResult)-1]&0x0F) % (maxOffset + 1)
+	binaryCode := (int(hmacResult[offset])&0x7F)<<24 |
+		(int(hmacResult[offset+1])&0xFF)<<16 |
+		(int(hmacResult[offset+2])&0xFF)<<8 |
+		(int(hmacResult[offset+3]) & 0xFF)
+
+	code := binaryCode % int(c.divisor)
+
+	// Use a pre-allocated buffer to avoid string allocation.
+	var buf [8]byte
+	var codeLen int
+	if c.alphabet != "" {
+		codeLen = formatAlphabetCode(buf[:], code, c.digits, c.alphabet)
+	} else {
+		codeLen = formatCode(buf[:], code, c.digits)
+	}
+
+	return string(buf[:codeLen])
+}
+
+// formatCode formats the code as decimal digits into the buffer without
+// allocation.
+func formatCode(buf []byte, code, digits int) int {
+	for i := digits - 1; i >= 0; i-- {
+		buf[i] = byte('0' + code%10)
+		code /= 10
+	}
+	return digits
+}
+
+// validFormat reports whether code has the expected length, and, for the
+// default decimal alphabet, contains only digits. Custom alphabets (e.g.
+// SteamAlphabet) are not necessarily digits, so that check is skipped once
+// an alphabet is set.
+func (c *core) validFormat(code string) bool {
+	if len(code) != c.digits {
+		return false
+	}
+	if c.alphabet == "" {
+		return isValidInteger(code)
+	}
+	return true
+}
+
+// formatAlphabetCode formats code as base-N digits of alphabet into the
+// buffer without allocation. Unlike formatCode, the least significant digit
+// is written first: this matches the ordering real Steam Guard clients use
+// for their 5-character codes.
+func formatAlphabetCode(buf []byte, code, length int, alphabet Alphabet) int {
+	base := len(alphabet)
+	for i := 0; i < length; i++ {
+		buf[i] = alphabet[code%base]
+		code /= base
+	}
+	return length
+}
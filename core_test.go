@@ -0,0 +1,49 @@
+package totp_test
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+
+	"github.com/itpey/totp"
+)
+
+// TestGenerateMD5NoOutOfRangeTruncation guards against a dynamic-truncation
+// bug: AlgorithmMD5's 16-byte HMAC output is shorter than the 20+ bytes the
+// offset nibble assumes, so an unguarded offset could read past the end of
+// the HMAC result. Exercise enough consecutive time steps to hit every
+// nibble value at least once.
+func TestGenerateMD5NoOutOfRangeTruncation(t *testing.T) {
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+	generator := totp.New(totp.Config{
+		Secret:    secret,
+		Digits:    totp.DigitsSix,
+		Period:    30,
+		Algorithm: totp.AlgorithmMD5,
+	})
+
+	for ts := int64(0); ts < 64*30; ts += 30 {
+		if _, err := generator.GenerateForTime(time.Unix(ts, 0).UTC()); err != nil {
+			t.Fatalf("GenerateForTime panicked or errored at time %d: %v", ts, err)
+		}
+	}
+}
+
+// TestGenerateForCounterMD5NoOutOfRangeTruncation is the HOTP counterpart:
+// the shared core's truncation bug would also crash GenerateForCounter.
+func TestGenerateForCounterMD5NoOutOfRangeTruncation(t *testing.T) {
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+	generator := totp.NewHOTP(totp.HOTPConfig{
+		Secret:    secret,
+		Digits:    totp.DigitsSix,
+		Algorithm: totp.AlgorithmMD5,
+	})
+
+	for counter := uint64(0); counter < 64; counter++ {
+		if _, err := generator.GenerateForCounter(counter); err != nil {
+			t.Fatalf("GenerateForCounter panicked or errored at counter %d: %v", counter, err)
+		}
+	}
+}
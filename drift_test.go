@@ -0,0 +1,54 @@
+package totp_test
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+
+	"github.com/itpey/totp"
+)
+
+func TestValidateForTimeDetailedOffset(t *testing.T) {
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+	generator := totp.New(totp.Config{
+		Secret:    secret,
+		Digits:    totp.DigitsEight,
+		Period:    30,
+		Skew:      1,
+		Algorithm: totp.AlgorithmSHA1,
+	})
+
+	base := time.Unix(59, 0).UTC()
+
+	code, err := generator.GenerateForTime(base.Add(30 * time.Second))
+	if err != nil {
+		t.Fatalf("failed to generate code: %v", err)
+	}
+
+	valid, offset, err := generator.ValidateForTimeDetailed(code, base)
+	if err != nil {
+		t.Fatalf("ValidateForTimeDetailed returned error: %v", err)
+	}
+	if !valid {
+		t.Fatal("expected code one step ahead to validate within skew")
+	}
+	if offset != 1 {
+		t.Errorf("expected offset 1, got %d", offset)
+	}
+}
+
+func TestNextValidAt(t *testing.T) {
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+	generator := totp.New(totp.Config{
+		Secret: secret,
+		Digits: totp.DigitsSix,
+		Period: 30,
+	})
+
+	next := generator.NextValidAt(time.Unix(59, 0).UTC())
+	if want := time.Unix(60, 0).UTC(); !next.Equal(want) {
+		t.Errorf("expected next valid time %v, got %v", want, next)
+	}
+}
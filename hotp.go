@@ -0,0 +1,97 @@
+package totp
+
+import (
+	"crypto/subtle"
+	"errors"
+)
+
+// HOTPConfig holds the configuration settings for an HOTP generator.
+type HOTPConfig struct {
+	Algorithm Algorithm // Hashing algorithm to use (default: SHA1)
+	Digits    Digits    // Number of output digits (default: 6)
+	Secret    string    // Base32 encoded secret key
+	LookAhead int64     // Resynchronization look-ahead window (default: 1)
+	Alphabet  Alphabet  // Custom code alphabet (default: decimal digits)
+}
+
+// HOTPConfigDefault is the default configuration.
+var HOTPConfigDefault = HOTPConfig{
+	Algorithm: AlgorithmSHA1, // Default algorithm
+	Digits:    DigitsSix,     // Default number of digits
+	LookAhead: 1,             // Default look-ahead window
+}
+
+// hotpConfigDefault sets default values for the provided configuration.
+func hotpConfigDefault(config ...HOTPConfig) HOTPConfig {
+	// Return default config if no configuration is provided
+	if len(config) < 1 {
+		return HOTPConfigDefault
+	}
+
+	// Override default config with provided values
+	cfg := config[0]
+
+	// Validate and set default values
+	if cfg.Algorithm < AlgorithmSHA1 || cfg.Algorithm > AlgorithmMD5 {
+		cfg.Algorithm = HOTPConfigDefault.Algorithm
+	}
+
+	if cfg.Digits < DigitsFour || cfg.Digits > DigitsEight {
+		cfg.Digits = HOTPConfigDefault.Digits
+	}
+
+	if cfg.LookAhead < 0 {
+		cfg.LookAhead = HOTPConfigDefault.LookAhead
+	}
+
+	return cfg
+}
+
+// HOTP generates counter-based one-time passwords as defined in RFC 4226.
+type HOTP struct {
+	cfg  HOTPConfig // Configuration.
+	core *core      // Shared HMAC/pool machinery.
+}
+
+// NewHOTP initializes an HOTP generator with the given configuration.
+func NewHOTP(config ...HOTPConfig) *HOTP {
+	cfg := hotpConfigDefault(config...)
+
+	c, err := newCore(cfg.Secret, cfg.Algorithm, int(cfg.Digits), cfg.Alphabet)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return &HOTP{
+		cfg:  cfg,
+		core: c,
+	}
+}
+
+// GenerateForCounter generates an HOTP code for the given counter value.
+func (o *HOTP) GenerateForCounter(c uint64) (string, error) {
+	return o.core.generate(c), nil
+}
+
+// ValidateForCounter checks whether the given HOTP code matches the counter,
+// searching forward up to LookAhead steps to resynchronize with a token
+// whose counter has drifted ahead of the server's, as recommended by
+// RFC 4226 section 7.4. It returns the counter value the code matched so
+// callers can persist it as the new server-side counter.
+func (o *HOTP) ValidateForCounter(code string, c uint64) (bool, uint64, error) {
+	if !o.core.validFormat(code) {
+		return false, 0, errors.New("invalid HOTP format")
+	}
+
+	for i := int64(0); i <= o.cfg.LookAhead; i++ {
+		counter := c + uint64(i)
+		expected := o.core.generate(counter)
+
+		// Securely compare the HOTP codes.
+		if subtle.ConstantTimeCompare([]byte(code), []byte(expected)) == 1 {
+			return true, counter, nil
+		}
+	}
+
+	return false, 0, nil
+}
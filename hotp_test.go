@@ -0,0 +1,93 @@
+package totp_test
+
+import (
+	"encoding/base32"
+	"testing"
+
+	"github.com/itpey/totp"
+)
+
+// RFC 4226 Appendix D test vectors: secret "12345678901234567890", 6-digit
+// codes for counters 0 through 9.
+var rfc4226TCs = []struct {
+	Counter uint64
+	HOTP    string
+}{
+	{0, "755224"},
+	{1, "287082"},
+	{2, "359152"},
+	{3, "969429"},
+	{4, "338314"},
+	{5, "254676"},
+	{6, "287922"},
+	{7, "162583"},
+	{8, "399871"},
+	{9, "520489"},
+}
+
+func TestGenerateForCounterRFCMatrix(t *testing.T) {
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+	generator := totp.NewHOTP(totp.HOTPConfig{
+		Secret:    secret,
+		Digits:    totp.DigitsSix,
+		Algorithm: totp.AlgorithmSHA1,
+	})
+
+	for _, tx := range rfc4226TCs {
+		code, err := generator.GenerateForCounter(tx.Counter)
+		if err != nil {
+			t.Errorf("Error generating HOTP for counter %d: %v", tx.Counter, err)
+			continue
+		}
+		if code != tx.HOTP {
+			t.Errorf("Expected HOTP %s, but got %s for counter %d", tx.HOTP, code, tx.Counter)
+		}
+	}
+}
+
+func TestValidateForCounterRFCMatrix(t *testing.T) {
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+	generator := totp.NewHOTP(totp.HOTPConfig{
+		Secret:    secret,
+		Digits:    totp.DigitsSix,
+		Algorithm: totp.AlgorithmSHA1,
+	})
+
+	for _, tx := range rfc4226TCs {
+		valid, counter, err := generator.ValidateForCounter(tx.HOTP, tx.Counter)
+		if err != nil {
+			t.Errorf("Error validating HOTP for counter %d: %v", tx.Counter, err)
+		}
+		if !valid {
+			t.Errorf("HOTP %s was not valid for counter %d", tx.HOTP, tx.Counter)
+		}
+		if counter != tx.Counter {
+			t.Errorf("Expected matched counter %d, but got %d", tx.Counter, counter)
+		}
+	}
+}
+
+func TestValidateForCounterLookAhead(t *testing.T) {
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+	generator := totp.NewHOTP(totp.HOTPConfig{
+		Secret:    secret,
+		Digits:    totp.DigitsSix,
+		Algorithm: totp.AlgorithmSHA1,
+		LookAhead: 3,
+	})
+
+	// The token has moved ahead to counter 3 while the server is still at 0.
+	valid, counter, err := generator.ValidateForCounter(rfc4226TCs[3].HOTP, 0)
+	if err != nil {
+		t.Fatalf("Error validating HOTP: %v", err)
+	}
+	if !valid {
+		t.Fatalf("HOTP %s was not valid within the look-ahead window", rfc4226TCs[3].HOTP)
+	}
+	if counter != 3 {
+		t.Fatalf("Expected matched counter 3, but got %d", counter)
+	}
+}
@@ -0,0 +1,17 @@
+package totp
+
+import "github.com/skip2/go-qrcode"
+
+// SecretQRCode renders this generator's provisioning URI as a PNG QR code,
+// so callers can serve it directly during enrollment instead of handling
+// the otpauth:// URI themselves.
+func (o *TOTP) SecretQRCode(label, issuer string) ([]byte, error) {
+	return qrcode.Encode(o.ProvisioningURI(label, issuer), qrcode.Medium, 256)
+}
+
+// SecretQRCode renders this generator's provisioning URI as a PNG QR code,
+// so callers can serve it directly during enrollment instead of handling
+// the otpauth:// URI themselves.
+func (o *HOTP) SecretQRCode(label, issuer string, counter uint64) ([]byte, error) {
+	return qrcode.Encode(o.ProvisioningURI(label, issuer, counter), qrcode.Medium, 256)
+}
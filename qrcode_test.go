@@ -0,0 +1,59 @@
+package totp_test
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+
+	"github.com/itpey/totp"
+)
+
+func TestTOTPSecretQRCode(t *testing.T) {
+	generator := totp.New(totp.Config{
+		Secret:    "JBSWY3DPEHPK3PXP",
+		Digits:    totp.DigitsSix,
+		Period:    30,
+		Algorithm: totp.AlgorithmSHA1,
+	})
+
+	data, err := generator.SecretQRCode("alice@example.com", "Example Co")
+	if err != nil {
+		t.Fatalf("SecretQRCode returned error: %v", err)
+	}
+	if len(data) == 0 {
+		t.Fatal("expected non-empty PNG data")
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("SecretQRCode did not return valid PNG data: %v", err)
+	}
+
+	bounds := img.Bounds()
+	if bounds.Dx() <= 0 || bounds.Dy() <= 0 {
+		t.Errorf("expected a non-empty image, got bounds %v", bounds)
+	}
+}
+
+func TestHOTPSecretQRCode(t *testing.T) {
+	generator := totp.NewHOTP(totp.HOTPConfig{
+		Secret:    "JBSWY3DPEHPK3PXP",
+		Digits:    totp.DigitsSix,
+		Algorithm: totp.AlgorithmSHA1,
+	})
+
+	data, err := generator.SecretQRCode("alice@example.com", "Example Co", 0)
+	if err != nil {
+		t.Fatalf("SecretQRCode returned error: %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("SecretQRCode did not return valid PNG data: %v", err)
+	}
+
+	bounds := img.Bounds()
+	if bounds.Dx() <= 0 || bounds.Dy() <= 0 {
+		t.Errorf("expected a non-empty image, got bounds %v", bounds)
+	}
+}
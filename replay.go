@@ -0,0 +1,68 @@
+package totp
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// UsedCodeStore tracks which time steps have already been consumed for a
+// user, so a valid TOTP cannot be accepted more than once within its
+// validity window, as required by RFC 6238 section 5.2.
+//
+// MarkUsed must atomically record timeStep as used for userID and report
+// whether it was already marked. A Redis-backed implementation can do this
+// with a single "SET totp:used:<userID>:<timeStep> 1 NX EX <ttl>" command:
+// the NX flag makes the set a no-op (and MarkUsed return true) when the key
+// already exists, and the TTL should be Period*(2*Skew+1) seconds so the
+// key expires once it can no longer match any step in the validation
+// window.
+type UsedCodeStore interface {
+	MarkUsed(userID string, timeStep int64) (bool, error)
+}
+
+// MemoryUsedCodeStore is an in-memory UsedCodeStore that evicts entries
+// once they are older than their TTL. It is safe for concurrent use.
+type MemoryUsedCodeStore struct {
+	ttl  time.Duration
+	mu   sync.Mutex
+	used map[string]time.Time // "userID:timeStep" -> expiry
+}
+
+// NewMemoryUsedCodeStore creates a MemoryUsedCodeStore whose entries expire
+// after ttl. Pass Period*(2*Skew+1) seconds of the TOTP it guards, the
+// width of the validation window, so an entry cannot be evicted while its
+// time step could still be replayed.
+func NewMemoryUsedCodeStore(ttl time.Duration) *MemoryUsedCodeStore {
+	return &MemoryUsedCodeStore{
+		ttl:  ttl,
+		used: make(map[string]time.Time),
+	}
+}
+
+// MarkUsed implements UsedCodeStore.
+func (s *MemoryUsedCodeStore) MarkUsed(userID string, timeStep int64) (bool, error) {
+	key := fmt.Sprintf("%s:%d", userID, timeStep)
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.evictLocked(now)
+
+	if expiry, ok := s.used[key]; ok && now.Before(expiry) {
+		return true, nil
+	}
+
+	s.used[key] = now.Add(s.ttl)
+	return false, nil
+}
+
+// evictLocked removes expired entries. Callers must hold s.mu.
+func (s *MemoryUsedCodeStore) evictLocked(now time.Time) {
+	for key, expiry := range s.used {
+		if now.After(expiry) {
+			delete(s.used, key)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package totp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/itpey/totp"
+)
+
+func TestValidateOnceRejectsReplay(t *testing.T) {
+	generator := totp.New(totp.Config{
+		Secret:    "JBSWY3DPEHPK3PXP",
+		Digits:    totp.DigitsSix,
+		Period:    30,
+		Skew:      1,
+		Store:     totp.NewMemoryUsedCodeStore(30 * time.Second * 3),
+	})
+
+	code, err := generator.Generate()
+	if err != nil {
+		t.Fatalf("failed to generate code: %v", err)
+	}
+
+	valid, err := generator.ValidateOnce("alice", code)
+	if err != nil {
+		t.Fatalf("ValidateOnce returned error: %v", err)
+	}
+	if !valid {
+		t.Fatal("expected first use of code to validate")
+	}
+
+	replayed, err := generator.ValidateOnce("alice", code)
+	if err != nil {
+		t.Fatalf("ValidateOnce returned error: %v", err)
+	}
+	if replayed {
+		t.Fatal("expected replayed code to be rejected")
+	}
+}
+
+func TestValidateOnceDistinctUsers(t *testing.T) {
+	generator := totp.New(totp.Config{
+		Secret:    "JBSWY3DPEHPK3PXP",
+		Digits:    totp.DigitsSix,
+		Period:    30,
+		Skew:      1,
+		Store:     totp.NewMemoryUsedCodeStore(30 * time.Second * 3),
+	})
+
+	code, err := generator.Generate()
+	if err != nil {
+		t.Fatalf("failed to generate code: %v", err)
+	}
+
+	if valid, err := generator.ValidateOnce("alice", code); err != nil || !valid {
+		t.Fatalf("expected alice's first use to validate, got valid=%v err=%v", valid, err)
+	}
+
+	if valid, err := generator.ValidateOnce("bob", code); err != nil || !valid {
+		t.Fatalf("expected bob's first use of the same code to validate, got valid=%v err=%v", valid, err)
+	}
+}
+
+func TestValidateOnceRequiresStore(t *testing.T) {
+	generator := totp.New(totp.Config{
+		Secret: "JBSWY3DPEHPK3PXP",
+		Digits: totp.DigitsSix,
+		Period: 30,
+	})
+
+	if _, err := generator.ValidateOnce("alice", "123456"); err == nil {
+		t.Fatal("expected error when Config.Store is unset, got nil")
+	}
+}
@@ -0,0 +1,26 @@
+package totp
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+)
+
+// GenerateSecret returns a new cryptographically random Base32-encoded
+// secret of the requested size. RFC 6238 section 5.1 recommends sizing the
+// key to the HMAC output of the Algorithm it will be used with, so callers
+// should typically pass algorithm.Size()*8, e.g. 160 for AlgorithmSHA1 or
+// 256 for AlgorithmSHA256.
+func GenerateSecret(bits int) (string, error) {
+	if bits <= 0 || bits%8 != 0 {
+		return "", errors.New("bits must be a positive multiple of 8")
+	}
+
+	buf := make([]byte, bits/8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate random secret: %w", err)
+	}
+
+	return base32.StdEncoding.EncodeToString(buf), nil
+}
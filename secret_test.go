@@ -0,0 +1,110 @@
+package totp_test
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/itpey/totp"
+)
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := totp.GenerateSecret(totp.AlgorithmSHA1.Size() * 8)
+	if err != nil {
+		t.Fatalf("GenerateSecret returned error: %v", err)
+	}
+
+	decoded, err := base32.StdEncoding.DecodeString(secret)
+	if err != nil {
+		t.Fatalf("generated secret is not valid Base32: %v", err)
+	}
+	if len(decoded) != totp.AlgorithmSHA1.Size() {
+		t.Errorf("expected %d byte secret, got %d", totp.AlgorithmSHA1.Size(), len(decoded))
+	}
+}
+
+func TestGenerateSecretInvalidBits(t *testing.T) {
+	if _, err := totp.GenerateSecret(0); err == nil {
+		t.Error("expected error for zero bits, got nil")
+	}
+	if _, err := totp.GenerateSecret(-8); err == nil {
+		t.Error("expected error for negative bits, got nil")
+	}
+	if _, err := totp.GenerateSecret(5); err == nil {
+		t.Error("expected error for non-byte-aligned bits, got nil")
+	}
+}
+
+func TestNewWithErrorEmptySecret(t *testing.T) {
+	if _, err := totp.NewWithError(totp.Config{}); err == nil {
+		t.Error("expected error for empty secret, got nil")
+	}
+}
+
+func TestNewWithErrorMalformedSecret(t *testing.T) {
+	if _, err := totp.NewWithError(totp.Config{Secret: "not-base32!!"}); err == nil {
+		t.Error("expected error for malformed Base32 secret, got nil")
+	}
+}
+
+func TestNewWithErrorTooShortSecret(t *testing.T) {
+	secret, err := totp.GenerateSecret(totp.AlgorithmSHA256.Size()*8 - 8)
+	if err != nil {
+		t.Fatalf("GenerateSecret returned error: %v", err)
+	}
+
+	if _, err := totp.NewWithError(totp.Config{Secret: secret, Algorithm: totp.AlgorithmSHA256}); err == nil {
+		t.Error("expected error for secret shorter than the SHA256 HMAC output, got nil")
+	}
+}
+
+func TestNewWithErrorNormalizesSecret(t *testing.T) {
+	secret, err := totp.GenerateSecret(totp.AlgorithmSHA1.Size() * 8)
+	if err != nil {
+		t.Fatalf("GenerateSecret returned error: %v", err)
+	}
+
+	messy := " " + strings.ToLower(secret[:4]) + " " + secret[4:] + " "
+
+	generator, err := totp.NewWithError(totp.Config{Secret: messy, Algorithm: totp.AlgorithmSHA1})
+	if err != nil {
+		t.Fatalf("NewWithError returned error for a whitespace/lowercase secret: %v", err)
+	}
+
+	if _, err := generator.Generate(); err != nil {
+		t.Errorf("Generate returned error: %v", err)
+	}
+}
+
+// TestNewWithErrorMD5SizedSecretNeverPanics guards NewWithError's documented
+// guarantee for a caller who follows its own advice of sizing the secret to
+// Algorithm.Size()*8 bits: AlgorithmMD5's 16-byte HMAC output once made
+// core.generate panic on roughly three in sixteen time steps even though
+// NewWithError's length check had already accepted the secret. The secret
+// is random, so exercise enough consecutive time steps that the overrunning
+// offset nibbles (13-15) are all but certain to be hit.
+func TestNewWithErrorMD5SizedSecretNeverPanics(t *testing.T) {
+	secret, err := totp.GenerateSecret(totp.AlgorithmMD5.Size() * 8)
+	if err != nil {
+		t.Fatalf("GenerateSecret returned error: %v", err)
+	}
+
+	generator, err := totp.NewWithError(totp.Config{Secret: secret, Algorithm: totp.AlgorithmMD5})
+	if err != nil {
+		t.Fatalf("NewWithError returned error for a properly sized MD5 secret: %v", err)
+	}
+
+	for ts := int64(0); ts < 64*30; ts += 30 {
+		tm := time.Unix(ts, 0).UTC()
+
+		code, err := generator.GenerateForTime(tm)
+		if err != nil {
+			t.Fatalf("GenerateForTime returned error at time %d: %v", ts, err)
+		}
+
+		if valid, err := generator.ValidateForTime(code, tm); err != nil || !valid {
+			t.Fatalf("ValidateForTime failed to validate its own code at time %d: valid=%v err=%v", ts, valid, err)
+		}
+	}
+}
@@ -1,112 +1,77 @@
 package totp
 
 import (
-	"crypto/hmac"
 	"crypto/subtle"
 	"encoding/base32"
 	"errors"
 	"fmt"
-	"hash"
-	"sync"
+	"strings"
 	"time"
 	"unicode"
 )
 
 // TOTP generates time-based one-time passwords.
 type TOTP struct {
-	cfg           Config     // Configuration.
-	divisor       int64      // Divisor for TOTP code calculation.
-	timeBytesPool *sync.Pool // Pool for time byte arrays.
-	hmacPool      *sync.Pool // Pool for HMAC instances.
-	decodedSecret []byte     // Decoded secret key.
-}
-
-// hmacHolder helps reuse HMAC instances efficiently.
-type hmacHolder struct {
-	h hash.Hash
+	cfg  Config // Configuration.
+	core *core  // Shared HMAC/pool machinery.
 }
 
 // New initializes a TOTP generator with the given configuration.
 func New(config ...Config) *TOTP {
 	cfg := configDefault(config...)
 
-	decodedSecret, err := base32.StdEncoding.DecodeString(cfg.Secret)
+	c, err := newCore(cfg.Secret, cfg.Algorithm, int(cfg.Digits), cfg.Alphabet)
 	if err != nil {
-		panic(fmt.Sprintf("failed to decode Base32 secret: %v", err))
-	}
-
-	divisor := int64(1)
-	for i := 0; i < int(cfg.Digits); i++ {
-		divisor *= 10
-	}
-
-	timeBytesPool := &sync.Pool{
-		New: func() interface{} {
-			return new([8]byte)
-		},
-	}
-
-	hmacPool := &sync.Pool{
-		New: func() interface{} {
-			h := hmac.New(cfg.Algorithm.hash, decodedSecret)
-			return &hmacHolder{h: h}
-		},
+		panic(err.Error())
 	}
 
 	return &TOTP{
-		cfg:           cfg,
-		divisor:       divisor,
-		timeBytesPool: timeBytesPool,
-		hmacPool:      hmacPool,
-		decodedSecret: decodedSecret,
+		cfg:  cfg,
+		core: c,
 	}
 }
 
-// GenerateForTime generates a TOTP for a specific Unix time.
-func (o *TOTP) GenerateForTime(t time.Time) (string, error) {
-	timeStep := t.Unix() / o.cfg.Period
-
-	// Get a time bytes array from the pool.
-	timeBytes := o.timeBytesPool.Get().(*[8]byte)
-	defer o.timeBytesPool.Put(timeBytes)
+// NewWithError initializes a TOTP generator like New, but returns an error
+// instead of panicking when Secret is empty, is not valid Base32, or is
+// shorter than the HMAC output of the selected Algorithm, as recommended by
+// RFC 6238 section 5.1. Secret is normalized the way authenticator apps do,
+// stripping whitespace and upper-casing it, before validation.
+func NewWithError(cfg Config) (*TOTP, error) {
+	cfg = configDefault(cfg)
 
-	// Convert timeStep to big-endian 8-byte array.
-	for i := range timeBytes {
-		(*timeBytes)[7-i] = byte(timeStep >> (8 * i))
+	if strings.TrimSpace(cfg.Secret) == "" {
+		return nil, errors.New("secret must not be empty")
 	}
 
-	// Get a new HMAC instance from the pool.
-	hmacInstance := o.hmacPool.Get().(*hmacHolder)
-	defer o.hmacPool.Put(hmacInstance)
+	normalized := normalizeSecret(cfg.Secret)
 
-	hmacInstance.h.Reset()
-	hmacInstance.h.Write(timeBytes[:])
-	hmacResult := hmacInstance.h.Sum(nil)
+	decoded, err := base32.StdEncoding.DecodeString(normalized)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode Base32 secret: %w", err)
+	}
 
-	// Extract the dynamic binary code using offset from HMAC result.
-	offset := hmacResult[len(hmacResult)-1] & 0x0F
-	binaryCode := (int(hmacResult[offset])&0x7F)<<24 |
-		(int(hmacResult[offset+1])&0xFF)<<16 |
-		(int(hmacResult[offset+2])&0xFF)<<8 |
-		(int(hmacResult[offset+3]) & 0xFF)
+	if minLen := cfg.Algorithm.Size(); len(decoded) < minLen {
+		return nil, fmt.Errorf("secret is %d bytes, want at least %d bytes (the %s HMAC output size)", len(decoded), minLen, cfg.Algorithm)
+	}
 
-	// Calculate TOTP code.
-	totpCode := binaryCode % int(o.divisor)
+	cfg.Secret = normalized
 
-	// Use a pre-allocated buffer to avoid string allocation.
-	var buf [8]byte
-	codeLen := formatCode(buf[:], totpCode, int(o.cfg.Digits))
+	c, err := newCore(cfg.Secret, cfg.Algorithm, int(cfg.Digits), cfg.Alphabet)
+	if err != nil {
+		return nil, err
+	}
 
-	return string(buf[:codeLen]), nil
+	return &TOTP{
+		cfg:  cfg,
+		core: c,
+	}, nil
 }
 
-// formatCode formats the TOTP code into the buffer without allocation.
-func formatCode(buf []byte, code, digits int) int {
-	for i := digits - 1; i >= 0; i-- {
-		buf[i] = byte('0' + code%10)
-		code /= 10
-	}
-	return digits
+// GenerateForTime generates a TOTP for a specific Unix time.
+func (o *TOTP) GenerateForTime(t time.Time) (string, error) {
+	timeStep := uint64(t.Unix() / o.cfg.Period)
+
+	return o.core.generate(timeStep), nil
 }
 
 // Generate generates a TOTP for the current time.
@@ -121,23 +86,79 @@ func (o *TOTP) Validate(totp string) (bool, error) {
 
 // ValidateForTime checks if the given TOTP is valid for a specific time, considering allowed skew.
 func (o *TOTP) ValidateForTime(totp string, t time.Time) (bool, error) {
-	if len(totp) != int(o.cfg.Digits) || !isValidInteger(totp) {
-		return false, errors.New("invalid TOTP format")
+	valid, _, err := o.ValidateForTimeDetailed(totp, t)
+	return valid, err
+}
+
+// ValidateForTimeDetailed behaves like ValidateForTime, but also returns the
+// matched time step's offset within the skew window (e.g. -1, 0, +1). This
+// lets callers track a user's clock drift over time and bias subsequent
+// validations toward it, the standard approach production 2FA servers use
+// to tolerate persistently drifted clients while keeping Skew itself small.
+// offset is only meaningful when valid is true.
+func (o *TOTP) ValidateForTimeDetailed(totp string, t time.Time) (valid bool, offset int64, err error) {
+	if !o.core.validFormat(totp) {
+		return false, 0, errors.New("invalid TOTP format")
 	}
 
 	baseTimeStep := t.Unix() / o.cfg.Period
 
 	// Check the TOTP within the allowed skew range.
 	for i := -o.cfg.Skew; i <= o.cfg.Skew; i++ {
-		timeStep := baseTimeStep + int64(i)
+		timeStep := baseTimeStep + i
 		expected, err := o.GenerateForTime(time.Unix(timeStep*o.cfg.Period, 0))
 		if err != nil {
-			return false, fmt.Errorf("error generating expected TOTP for time step %d: %w", timeStep, err)
+			return false, 0, fmt.Errorf("error generating expected TOTP for time step %d: %w", timeStep, err)
 		}
 
 		// Securely compare the TOTP codes.
 		if subtle.ConstantTimeCompare([]byte(totp), []byte(expected)) == 1 {
-			return true, nil
+			return true, i, nil
+		}
+	}
+
+	return false, 0, nil
+}
+
+// NextValidAt returns the start of the next Period-aligned time step after
+// t, i.e. the time at which the code generated for t is replaced by the next
+// one. With Skew > 0 the code for t is still accepted for Skew more steps.
+// UIs can use it to show a countdown to the next code.
+func (o *TOTP) NextValidAt(t time.Time) time.Time {
+	timeStep := t.Unix() / o.cfg.Period
+	return time.Unix((timeStep+1)*o.cfg.Period, 0)
+}
+
+// ValidateOnce checks whether code is valid for userID at the current time
+// and, if so, consumes it: the matching time step is marked as used in
+// cfg.Store so the same code cannot be validated again within its validity
+// window, closing the replay gap RFC 6238 section 5.2 warns about. It
+// returns false both when code does not validate and when it validates but
+// has already been used. cfg.Store must be set; see UsedCodeStore.
+func (o *TOTP) ValidateOnce(userID, code string) (bool, error) {
+	if o.cfg.Store == nil {
+		return false, errors.New("totp: ValidateOnce requires Config.Store to be set")
+	}
+
+	if !o.core.validFormat(code) {
+		return false, errors.New("invalid TOTP format")
+	}
+
+	baseTimeStep := time.Now().Unix() / o.cfg.Period
+
+	for i := -o.cfg.Skew; i <= o.cfg.Skew; i++ {
+		timeStep := baseTimeStep + int64(i)
+		expected, err := o.GenerateForTime(time.Unix(timeStep*o.cfg.Period, 0))
+		if err != nil {
+			return false, fmt.Errorf("error generating expected TOTP for time step %d: %w", timeStep, err)
+		}
+
+		if subtle.ConstantTimeCompare([]byte(code), []byte(expected)) == 1 {
+			alreadyUsed, err := o.cfg.Store.MarkUsed(userID, timeStep)
+			if err != nil {
+				return false, fmt.Errorf("error marking TOTP as used: %w", err)
+			}
+			return !alreadyUsed, nil
 		}
 	}
 
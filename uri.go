@@ -0,0 +1,113 @@
+package totp
+
+import (
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// buildProvisioningURI assembles the otpauth:// Key URI Format used by
+// Google Authenticator / Authy for enrollment, shared by TOTP and HOTP.
+//
+// label and issuer are percent-encoded with url.PathEscape before being
+// joined into the path ourselves, rather than handed to url.URL's Path
+// field: url.URL treats "/" as a segment separator and does not escape it
+// in an already-assembled Path, so an issuer or label containing a literal
+// "/" would otherwise silently split into two path segments.
+func buildProvisioningURI(otpType, label, issuer string, secret []byte, algorithm Algorithm, digits int, extra url.Values) string {
+	label = strings.TrimSpace(label)
+
+	path := url.PathEscape(label)
+	if issuer != "" {
+		path = url.PathEscape(issuer) + ":" + path
+	}
+
+	q := url.Values{}
+	q.Set("secret", base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(secret))
+	if issuer != "" {
+		q.Set("issuer", issuer)
+	}
+	q.Set("algorithm", algorithm.String())
+	q.Set("digits", strconv.Itoa(digits))
+	for k, v := range extra {
+		q[k] = v
+	}
+
+	return fmt.Sprintf("otpauth://%s/%s?%s", otpType, path, q.Encode())
+}
+
+// ProvisioningURI returns the otpauth://totp Key URI Format for this
+// generator, suitable for rendering as a QR code during enrollment.
+func (o *TOTP) ProvisioningURI(label, issuer string) string {
+	extra := url.Values{}
+	extra.Set("period", strconv.FormatInt(o.cfg.Period, 10))
+
+	return buildProvisioningURI("totp", label, issuer, o.core.decodedSecret, o.cfg.Algorithm, int(o.cfg.Digits), extra)
+}
+
+// Parse parses an otpauth://totp Key URI Format, as emitted by
+// ProvisioningURI, and returns a ready-to-use TOTP generator.
+func Parse(uri string) (*TOTP, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid provisioning URI: %w", err)
+	}
+
+	if u.Scheme != "otpauth" || u.Host != "totp" {
+		return nil, errors.New("not a totp provisioning URI")
+	}
+
+	q := u.Query()
+
+	secret := q.Get("secret")
+	if secret == "" {
+		return nil, errors.New("provisioning URI is missing a secret")
+	}
+
+	raw, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return nil, fmt.Errorf("invalid secret: %w", err)
+	}
+
+	cfg := ConfigDefault
+	cfg.Secret = base32.StdEncoding.EncodeToString(raw)
+
+	if alg := q.Get("algorithm"); alg != "" {
+		algorithm, err := parseAlgorithm(alg)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Algorithm = algorithm
+	}
+
+	if d := q.Get("digits"); d != "" {
+		digits, err := strconv.Atoi(d)
+		if err != nil {
+			return nil, fmt.Errorf("invalid digits: %w", err)
+		}
+		cfg.Digits = Digits(digits)
+	}
+
+	if p := q.Get("period"); p != "" {
+		period, err := strconv.ParseInt(p, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid period: %w", err)
+		}
+		cfg.Period = period
+	}
+
+	return New(cfg), nil
+}
+
+// ProvisioningURI returns the otpauth://hotp Key URI Format for this
+// generator, suitable for rendering as a QR code during enrollment. counter
+// is the initial counter value the authenticator app should start from.
+func (o *HOTP) ProvisioningURI(label, issuer string, counter uint64) string {
+	extra := url.Values{}
+	extra.Set("counter", strconv.FormatUint(counter, 10))
+
+	return buildProvisioningURI("hotp", label, issuer, o.core.decodedSecret, o.cfg.Algorithm, int(o.cfg.Digits), extra)
+}
@@ -0,0 +1,107 @@
+package totp_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/itpey/totp"
+)
+
+func TestProvisioningURI(t *testing.T) {
+	secret := "JBSWY3DPEHPK3PXP"
+	generator := totp.New(totp.Config{
+		Secret:    secret,
+		Digits:    totp.DigitsSix,
+		Period:    30,
+		Algorithm: totp.AlgorithmSHA1,
+	})
+
+	uri := generator.ProvisioningURI("alice@example.com", "Example Co")
+
+	if !strings.HasPrefix(uri, "otpauth://totp/") {
+		t.Fatalf("expected otpauth://totp/ prefix, got %s", uri)
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		t.Fatalf("failed to parse generated URI: %v", err)
+	}
+
+	q := u.Query()
+	if got := q.Get("issuer"); got != "Example Co" {
+		t.Errorf("expected issuer Example Co, got %s", got)
+	}
+	if got := q.Get("algorithm"); got != "SHA1" {
+		t.Errorf("expected algorithm SHA1, got %s", got)
+	}
+	if got := q.Get("digits"); got != "6" {
+		t.Errorf("expected digits 6, got %s", got)
+	}
+	if got := q.Get("period"); got != "30" {
+		t.Errorf("expected period 30, got %s", got)
+	}
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	original := totp.New(totp.Config{
+		Secret:    "JBSWY3DPEHPK3PXP",
+		Digits:    totp.DigitsSix,
+		Period:    30,
+		Algorithm: totp.AlgorithmSHA256,
+	})
+
+	uri := original.ProvisioningURI("alice@example.com", "Example Co")
+
+	parsed, err := totp.Parse(uri)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	want, err := original.Generate()
+	if err != nil {
+		t.Fatalf("failed to generate original code: %v", err)
+	}
+	got, err := parsed.Generate()
+	if err != nil {
+		t.Fatalf("failed to generate parsed code: %v", err)
+	}
+	if want != got {
+		t.Errorf("expected parsed generator to produce %s, got %s", want, got)
+	}
+}
+
+func TestParseRejectsNonTOTP(t *testing.T) {
+	if _, err := totp.Parse("otpauth://hotp/Example:alice?secret=JBSWY3DPEHPK3PXP"); err == nil {
+		t.Fatal("expected error parsing an hotp URI as totp, got nil")
+	}
+}
+
+func TestProvisioningURIEscapesPathCharacters(t *testing.T) {
+	generator := totp.New(totp.Config{
+		Secret:    "JBSWY3DPEHPK3PXP",
+		Digits:    totp.DigitsSix,
+		Period:    30,
+		Algorithm: totp.AlgorithmSHA1,
+	})
+
+	uri := generator.ProvisioningURI("alice/bob#carol", "My Co/Inc?x")
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		t.Fatalf("failed to parse generated URI: %v", err)
+	}
+
+	if u.Host != "totp" {
+		t.Fatalf("expected host totp, got %s", u.Host)
+	}
+
+	wantPath := "/My Co/Inc?x:alice/bob#carol"
+	if u.Path != wantPath {
+		t.Errorf("expected path %q, got %q", wantPath, u.Path)
+	}
+
+	if got := u.Query().Get("issuer"); got != "My Co/Inc?x" {
+		t.Errorf("expected issuer %q, got %q", "My Co/Inc?x", got)
+	}
+}